@@ -43,6 +43,7 @@ program
   .option('-m, --metrics <patterns...>', 'Metric patterns to include')
   .option('-e, --exclude <patterns...>', 'Metric patterns to exclude')
   .option('-l, --layout <preference>', 'Layout preference (compact|balanced|detailed)', 'balanced')
+  .option('--variables <names...>', 'Template variables to add (host|ring|process_group|profile)')
   .option('-o, --output <file>', 'Save dashboard to file')
   .option('-d, --deploy', 'Deploy dashboard immediately')
   .option('-i, --interactive', 'Interactive mode')
@@ -115,7 +116,8 @@ program
           include: options.metrics || answers.metrics,
           exclude: options.exclude || answers.exclude
         },
-        layoutPreference: answers.layout
+        layoutPreference: answers.layout,
+        variables: options.variables || []
       };
       
       options.deploy = answers.deploy;
@@ -128,7 +130,8 @@ program
           include: options.metrics || ['*'],
           exclude: options.exclude || []
         },
-        layoutPreference: options.layout
+        layoutPreference: options.layout,
+        variables: options.variables || []
       };
     }
     
@@ -142,6 +145,9 @@ program
       console.log(chalk.blue(`  Metrics used: ${result.metadata.metricsUsed}`));
       console.log(chalk.blue(`  Widgets created: ${result.metadata.widgetsCreated}`));
       console.log(chalk.blue(`  Template: ${result.metadata.template}`));
+      if (result.metadata.variables.length > 0) {
+        console.log(chalk.blue(`  Variables: ${result.metadata.variables.join(', ')}`));
+      }
       
       if (options.output) {
         const outputPath = path.resolve(options.output);
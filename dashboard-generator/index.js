@@ -4,6 +4,7 @@ const MetricClassifier = require('./lib/metric-classifier');
 const DashboardTemplateEngine = require('./lib/template-engine');
 const QueryBuilder = require('./lib/query-builder');
 const LayoutOptimizer = require('./lib/layout-optimizer');
+const TemplateVariableBuilder = require('./lib/template-variables');
 
 // Main entry point
 class DashboardGenerator {
@@ -62,7 +63,8 @@ module.exports = {
   MetricClassifier,
   DashboardTemplateEngine,
   QueryBuilder,
-  LayoutOptimizer
+  LayoutOptimizer,
+  TemplateVariableBuilder
 };
 
 // CLI usage example
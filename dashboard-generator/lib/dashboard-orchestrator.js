@@ -3,6 +3,7 @@ const MetricClassifier = require('./metric-classifier');
 const DashboardTemplateEngine = require('./template-engine');
 const QueryBuilder = require('./query-builder');
 const LayoutOptimizer = require('./layout-optimizer');
+const TemplateVariableBuilder = require('./template-variables');
 const https = require('https');
 
 class DashboardOrchestrator {
@@ -17,6 +18,7 @@ class DashboardOrchestrator {
     this.templateEngine = new DashboardTemplateEngine();
     this.queryBuilder = new QueryBuilder();
     this.layoutOptimizer = new LayoutOptimizer(config.layoutOptions || {});
+    this.variableBuilder = new TemplateVariableBuilder(this.accountId);
     
     this.dashboardCache = new Map();
   }
@@ -29,7 +31,8 @@ class DashboardOrchestrator {
       metrics = {},
       layoutPreference = 'balanced',
       timeRange = '1 hour',
-      autoRefresh = true
+      autoRefresh = true,
+      variables = []
     } = options;
 
     try {
@@ -68,7 +71,8 @@ class DashboardOrchestrator {
         description,
         layout,
         timeRange,
-        autoRefresh
+        autoRefresh,
+        variables
       });
       
       // Step 7: Validate dashboard
@@ -83,6 +87,7 @@ class DashboardOrchestrator {
           metricsUsed: classifiedMetrics.length,
           widgetsCreated: widgets.length,
           template: selectedTemplate.name,
+          variables: dashboard.variables.map(v => v.name),
           generatedAt: new Date().toISOString()
         }
       };
@@ -285,7 +290,7 @@ class DashboardOrchestrator {
   }
 
   buildDashboardStructure(options) {
-    const { name, description, layout, timeRange, autoRefresh } = options;
+    const { name, description, layout, timeRange, autoRefresh, variables = [] } = options;
     
     const pages = [{
       name: 'Main',
@@ -296,7 +301,7 @@ class DashboardOrchestrator {
           [widget.type]: {
             nrqlQueries: [{
               accountId: parseInt(this.accountId),
-              query: widget.query
+              query: this.variableBuilder.applyToQuery(widget.query, variables)
             }]
           }
         },
@@ -317,7 +322,7 @@ class DashboardOrchestrator {
       description,
       permissions: 'PUBLIC_READ_WRITE',
       pages,
-      variables: []
+      variables: this.variableBuilder.buildVariables(variables)
     };
   }
 
@@ -362,6 +367,23 @@ class DashboardOrchestrator {
     
     errors.push(...layoutValidation.errors);
     
+    // Validate template variable references
+    const variableNames = new Set((dashboard.variables || []).map(v => v.name));
+    dashboard.pages.forEach((page, pageIndex) => {
+      page.widgets.forEach((widget, widgetIndex) => {
+        const config = widget.configuration || widget.rawConfiguration || {};
+        const vizConfig = config.nrqlQueries ? config : config[Object.keys(config)[0]] || {};
+        
+        (vizConfig.nrqlQueries || []).forEach(nrqlQuery => {
+          this.variableBuilder.findReferences(nrqlQuery.query || '')
+            .filter(name => !variableNames.has(name))
+            .forEach(name => {
+              errors.push(`Widget ${widgetIndex + 1} on page ${pageIndex + 1} references undefined variable '${name}'`);
+            });
+        });
+      });
+    });
+    
     return {
       valid: errors.length === 0,
       errors
@@ -3,8 +3,12 @@
  * Provides templates and patterns for automatic dashboard generation
  */
 
+const TemplateVariableBuilder = require('./template-variables');
+
 class DashboardTemplateEngine {
   constructor() {
+    this.variableBuilder = new TemplateVariableBuilder(process.env.NEW_RELIC_ACCOUNT_ID || '3630072');
+
     // Dashboard templates
     this.templates = {
       'system-health': {
@@ -222,11 +226,14 @@ class DashboardTemplateEngine {
       throw new Error(`Template '${templateName}' not found`);
     }
 
+    const variables = options.variables || template.variables || [];
+
     const dashboard = {
       name: options.name || template.name,
       description: options.description || template.description,
       permissions: options.permissions || 'PUBLIC_READ_WRITE',
-      pages: []
+      pages: [],
+      variables: this.variableBuilder.buildVariables(variables)
     };
 
     // Generate pages based on template sections
@@ -239,7 +246,7 @@ class DashboardTemplateEngine {
 
       // Generate widgets for this section
       section.widgets.forEach(widgetDef => {
-        const widget = this.generateWidget(widgetDef, availableMetrics);
+        const widget = this.generateWidget(widgetDef, availableMetrics, variables);
         if (widget) {
           page.widgets.push(widget);
         }
@@ -260,7 +267,7 @@ class DashboardTemplateEngine {
   }
 
   // Generate a single widget
-  generateWidget(widgetDef, availableMetrics) {
+  generateWidget(widgetDef, availableMetrics, variables = []) {
     const widgetType = this.widgetTypes[widgetDef.type];
     if (!widgetType) return null;
 
@@ -282,7 +289,7 @@ class DashboardTemplateEngine {
       rawConfiguration: {
         nrqlQueries: [{
           accountIds: [parseInt(process.env.NEW_RELIC_ACCOUNT_ID || '3630072')],
-          query: query
+          query: this.variableBuilder.applyToQuery(query, variables)
         }],
         ...this.getVisualizationConfig(widgetType.visualization)
       }
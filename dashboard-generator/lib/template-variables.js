@@ -0,0 +1,275 @@
+/**
+ * Dashboard Template Variables
+ * Builds New Relic dashboard variables (facet pickers) and applies them to widget queries
+ */
+
+class TemplateVariableBuilder {
+  constructor(accountId) {
+    this.accountId = accountId;
+
+    // Common NRDOT facet pickers, referenced by name instead of full definitions
+    this.presets = {
+      'host': {
+        name: 'host',
+        title: 'Host',
+        type: 'nrql',
+        attribute: 'host.name',
+        // Built-in templates pin a single host; the picker replaces that pin
+        replaces: ['host.id'],
+        query: 'SELECT uniques(host.name) FROM Metric WHERE metricName LIKE \'process.%\' SINCE 1 day ago',
+        multiple: true
+      },
+      'ring': {
+        name: 'ring',
+        title: 'Ring',
+        type: 'nrql',
+        attribute: 'nrdot.ring',
+        query: 'SELECT uniques(nrdot.ring) FROM Metric WHERE nrdot.ring IS NOT NULL SINCE 1 day ago',
+        multiple: true
+      },
+      'process_group': {
+        name: 'process_group',
+        title: 'Process Group',
+        type: 'nrql',
+        attribute: 'process.classification',
+        query: 'SELECT uniques(process.classification) FROM Metric WHERE process.classification IS NOT NULL SINCE 1 day ago',
+        multiple: true
+      },
+      'profile': {
+        name: 'profile',
+        title: 'Optimization Profile',
+        type: 'enum',
+        attribute: 'nrdot.profile',
+        values: ['baseline', 'conservative', 'balanced', 'aggressive'],
+        multiple: true
+      }
+    };
+
+    this.typeMap = {
+      'enum': 'ENUM',
+      'nrql': 'NRQL',
+      'string': 'STRING'
+    };
+  }
+
+  // Resolve preset names and partial definitions into full definitions
+  resolve(definitions = []) {
+    return definitions.map(def => {
+      if (typeof def === 'string') {
+        const preset = this.presets[def];
+        if (!preset) {
+          throw new Error(`Unknown template variable preset '${def}'`);
+        }
+        return { ...preset };
+      }
+
+      if (def.preset) {
+        const preset = this.presets[def.preset];
+        if (!preset) {
+          throw new Error(`Unknown template variable preset '${def.preset}'`);
+        }
+        const { preset: _preset, ...overrides } = def;
+        return { ...preset, ...overrides };
+      }
+
+      return { ...def };
+    });
+  }
+
+  validate(definitions) {
+    const errors = [];
+    const seen = new Set();
+
+    definitions.forEach((def, index) => {
+      const label = def.name ? `Variable '${def.name}'` : `Variable ${index + 1}`;
+
+      if (!def.name) {
+        errors.push(`${label} has no name`);
+      } else if (!/^[A-Za-z_][A-Za-z0-9_]*$/.test(def.name)) {
+        errors.push(`${label} name must contain only letters, digits and underscores`);
+      } else if (seen.has(def.name)) {
+        errors.push(`${label} is defined more than once`);
+      } else {
+        seen.add(def.name);
+      }
+
+      if (!this.typeMap[def.type]) {
+        errors.push(`${label} has unsupported type '${def.type}' (expected enum, nrql or string)`);
+      } else if (def.type === 'enum' && (!Array.isArray(def.values) || def.values.length === 0)) {
+        errors.push(`${label} of type enum requires at least one value`);
+      } else if (def.type === 'nrql' && !def.query) {
+        errors.push(`${label} of type nrql requires a query`);
+      } else if (def.type === 'string' && def.attribute && (def.default === undefined || def.default === '')) {
+        // An empty string would filter every widget down to attribute = ''
+        errors.push(`${label} of type string with an attribute requires a default value`);
+      }
+    });
+
+    return {
+      valid: errors.length === 0,
+      errors
+    };
+  }
+
+  // Build NerdGraph DashboardVariableInput objects
+  buildVariables(definitions = []) {
+    const resolved = this.resolve(definitions);
+
+    const validation = this.validate(resolved);
+    if (!validation.valid) {
+      throw new Error(`Invalid template variables: ${validation.errors.join(', ')}`);
+    }
+
+    return resolved.map(def => this.buildVariable(def));
+  }
+
+  buildVariable(def) {
+    const variable = {
+      name: def.name,
+      title: def.title || def.name,
+      type: this.typeMap[def.type],
+      isMultiSelection: def.type !== 'string' && def.multiple !== false,
+      replacementStrategy: def.type === 'string' ? 'STRING' : (def.replacementStrategy || 'STRING')
+    };
+
+    if (def.type === 'enum') {
+      variable.items = def.values.map(value => ({
+        title: String(value),
+        value: String(value)
+      }));
+    }
+
+    if (def.type === 'nrql') {
+      variable.nrqlQuery = {
+        accountIds: [parseInt(this.accountId)],
+        query: def.query
+      };
+      variable.options = { ignoreTimeRange: def.ignoreTimeRange !== false };
+    }
+
+    variable.defaultValues = this.buildDefaultValues(def);
+
+    return variable;
+  }
+
+  buildDefaultValues(def) {
+    let defaults = def.default;
+
+    if (defaults === undefined) {
+      // Select everything by default so the unfiltered view still renders
+      defaults = def.type === 'string' ? '' : '*';
+    }
+
+    return [].concat(defaults).map(value => ({
+      value: { string: String(value) }
+    }));
+  }
+
+  // Add a filter for each attribute-bound variable to an NRQL query
+  applyToQuery(query, definitions = []) {
+    const resolved = this.resolve(definitions)
+      .filter(def => def.attribute && !query.includes(`{{${def.name}}}`));
+
+    if (resolved.length === 0) {
+      return query;
+    }
+
+    const condition = resolved.map(def => {
+      const operator = def.type === 'string' ? '=' : 'IN';
+      const placeholder = def.type === 'string' ? `{{${def.name}}}` : `({{${def.name}}})`;
+      return `${def.attribute} ${operator} ${placeholder}`;
+    }).join(' AND ');
+
+    const clauses = this.findClauses(query);
+    const from = clauses.find(clause => clause.keyword === 'FROM');
+    if (!from) {
+      return query;
+    }
+
+    const where = clauses.find(clause => clause.keyword === 'WHERE');
+    if (!where) {
+      // Insert right after the FROM clause; works for SELECT-first and FROM-first queries
+      const fromMatch = query.slice(from.index).match(/^FROM\s+(?:`[^`]+`|[\w.]+)(?:\s*,\s*(?:`[^`]+`|[\w.]+))*/i);
+      if (!fromMatch) {
+        return query;
+      }
+      const end = from.index + fromMatch[0].length;
+      return `${query.slice(0, end)} WHERE ${condition}${query.slice(end)}`;
+    }
+
+    // The existing predicate runs until the next top-level clause
+    const next = clauses.find(clause => clause.index > where.index && clause.keyword !== 'WHERE');
+    const end = next ? next.index : query.length;
+    const predicate = this.removePins(
+      query.slice(where.index + 'WHERE'.length, end).trim(),
+      resolved.flatMap(def => def.replaces || [])
+    );
+
+    const filtered = predicate ? `${condition} AND (${predicate})` : condition;
+    const tail = query.slice(end);
+
+    return `${query.slice(0, where.index)}WHERE ${filtered}${tail ? ` ${tail.trimStart()}` : ''}`;
+  }
+
+  // Locate top-level NRQL clause keywords, skipping quoted text and parentheses (e.g. FILTER(WHERE ...))
+  findClauses(query) {
+    const keywords = ['SELECT', 'FROM', 'WHERE', 'FACET', 'SINCE', 'UNTIL', 'TIMESERIES', 'LIMIT', 'OFFSET', 'COMPARE', 'EXTRAPOLATE', 'SLIDE', 'WITH', 'ORDER'];
+    const clauses = [];
+    let depth = 0;
+    let quote = null;
+
+    for (let i = 0; i < query.length; i++) {
+      const char = query[i];
+
+      if (quote) {
+        if (char === quote) quote = null;
+        continue;
+      }
+
+      if (char === '\'' || char === '"' || char === '`') {
+        quote = char;
+      } else if (char === '(') {
+        depth++;
+      } else if (char === ')') {
+        depth--;
+      } else if (depth === 0 && /[A-Za-z]/.test(char) && (i === 0 || /[\s)]/.test(query[i - 1]))) {
+        const word = query.slice(i).match(/^[A-Za-z]+/)[0];
+        if (keywords.includes(word.toUpperCase())) {
+          clauses.push({ keyword: word.toUpperCase(), index: i });
+        }
+        i += word.length - 1;
+      }
+    }
+
+    return clauses;
+  }
+
+  // Drop top-level `attribute = 'literal'` conditions that a variable takes over
+  removePins(predicate, attributes) {
+    if (attributes.length === 0 || /\bOR\b/i.test(predicate)) {
+      return predicate;
+    }
+
+    return predicate
+      .split(/\s+AND\s+/i)
+      .filter(part => !attributes.some(attribute =>
+        new RegExp(`^${attribute.replace(/\./g, '\\.')}\\s*=\\s*'[^']*'$`).test(part.trim())
+      ))
+      .join(' AND ');
+  }
+
+  // Find {{name}} references in a query
+  findReferences(query) {
+    const references = new Set();
+    const pattern = /\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}/g;
+    let match;
+
+    while ((match = pattern.exec(query)) !== null) {
+      references.add(match[1]);
+    }
+
+    return [...references];
+  }
+}
+
+module.exports = TemplateVariableBuilder;
@@ -0,0 +1,206 @@
+/**
+ * Statistical helpers for comparing experiment results
+ */
+
+// Two-sided critical values of the standard normal distribution
+const Z_VALUES = {
+  0.01: 2.576,
+  0.05: 1.96,
+  0.1: 1.645
+};
+
+// One-sided critical values for common statistical power targets
+const POWER_Z_VALUES = {
+  0.8: 0.842,
+  0.9: 1.282
+};
+
+/**
+ * Summarizes a list of samples
+ * @param {number[]} values - Samples
+ * @returns {{count: number, mean: number, variance: number}}
+ */
+function summarize(values) {
+  const count = values.length;
+  if (count === 0) {
+    return { count: 0, mean: 0, variance: 0 };
+  }
+
+  const mean = values.reduce((a, b) => a + b, 0) / count;
+  const variance = count > 1
+    ? values.reduce((sum, value) => sum + (value - mean) ** 2, 0) / (count - 1)
+    : 0;
+
+  return { count, mean, variance };
+}
+
+/**
+ * Natural log of the gamma function (Lanczos approximation)
+ * @param {number} x - Positive input
+ */
+function logGamma(x) {
+  const coefficients = [
+    76.18009172947146, -86.50532032941677, 24.01409824083091,
+    -1.231739572450155, 0.1208650973866179e-2, -0.5395239384953e-5
+  ];
+
+  let y = x;
+  const tmp = x + 5.5 - (x + 0.5) * Math.log(x + 5.5);
+  let series = 1.000000000190015;
+
+  for (const coefficient of coefficients) {
+    series += coefficient / ++y;
+  }
+
+  return -tmp + Math.log(2.5066282746310005 * series / x);
+}
+
+/**
+ * Continued fraction used by the regularized incomplete beta function
+ */
+function betaContinuedFraction(a, b, x) {
+  const maxIterations = 200;
+  const epsilon = 3e-14;
+  const tiny = 1e-300;
+
+  let c = 1;
+  let d = 1 - (a + b) * x / (a + 1);
+  if (Math.abs(d) < tiny) d = tiny;
+  d = 1 / d;
+  let result = d;
+
+  for (let m = 1; m <= maxIterations; m++) {
+    const m2 = 2 * m;
+
+    let aa = m * (b - m) * x / ((a + m2 - 1) * (a + m2));
+    d = 1 + aa * d;
+    if (Math.abs(d) < tiny) d = tiny;
+    c = 1 + aa / c;
+    if (Math.abs(c) < tiny) c = tiny;
+    d = 1 / d;
+    result *= d * c;
+
+    aa = -(a + m) * (a + b + m) * x / ((a + m2) * (a + m2 + 1));
+    d = 1 + aa * d;
+    if (Math.abs(d) < tiny) d = tiny;
+    c = 1 + aa / c;
+    if (Math.abs(c) < tiny) c = tiny;
+    d = 1 / d;
+    const delta = d * c;
+    result *= delta;
+
+    if (Math.abs(delta - 1) < epsilon) break;
+  }
+
+  return result;
+}
+
+/**
+ * Regularized incomplete beta function I_x(a, b)
+ */
+function incompleteBeta(x, a, b) {
+  if (x <= 0) return 0;
+  if (x >= 1) return 1;
+
+  const front = Math.exp(
+    logGamma(a + b) - logGamma(a) - logGamma(b) + a * Math.log(x) + b * Math.log(1 - x)
+  );
+
+  if (x < (a + 1) / (a + b + 2)) {
+    return front * betaContinuedFraction(a, b, x) / a;
+  }
+
+  return 1 - front * betaContinuedFraction(b, a, 1 - x) / b;
+}
+
+/**
+ * Two-sided p-value for a Student's t statistic
+ * @param {number} t - t statistic
+ * @param {number} df - Degrees of freedom
+ */
+function tTestPValue(t, df) {
+  return incompleteBeta(df / (df + t * t), df / 2, 0.5);
+}
+
+/**
+ * Welch's t-test for two independent samples with unequal variances
+ * @param {number[]} control - Per-interval samples of the control arm
+ * @param {number[]} test - Per-interval samples of the test arm
+ * @param {number} alpha - Significance level
+ * @returns {{t: number, df: number, pValue: number, significant: boolean}|null}
+ */
+function welchTTest(control, test, alpha = 0.05) {
+  const a = summarize(control);
+  const b = summarize(test);
+
+  if (a.count < 2 || b.count < 2) {
+    return null;
+  }
+
+  const errorA = a.variance / a.count;
+  const errorB = b.variance / b.count;
+  const standardError = Math.sqrt(errorA + errorB);
+
+  if (standardError === 0) {
+    // Constant samples: any difference is exact, no difference is noise-free
+    const identical = a.mean === b.mean;
+    return { t: identical ? 0 : Infinity, df: a.count + b.count - 2, pValue: identical ? 1 : 0, significant: !identical };
+  }
+
+  const t = (b.mean - a.mean) / standardError;
+  const df = (errorA + errorB) ** 2 /
+    ((errorA ** 2) / (a.count - 1) + (errorB ** 2) / (b.count - 1));
+  const pValue = tTestPValue(t, df);
+
+  return { t, df, pValue, significant: pValue < alpha };
+}
+
+/**
+ * Keeps only samples whose query windows do not overlap
+ * Consecutive samples of a `SINCE 1 minute ago` query polled every 30s share half their
+ * window, so treating them as independent overstates significance.
+ * @param {number[]} values - Per-interval samples in collection order
+ * @param {number} windowSeconds - Time window each sample aggregates over
+ * @param {number} intervalSeconds - Time between samples
+ * @returns {{values: number[], step: number}} Thinned samples and the stride used
+ */
+function thinOverlapping(values, windowSeconds, intervalSeconds) {
+  const step = Math.max(1, Math.ceil(windowSeconds / intervalSeconds));
+  return {
+    values: values.filter((_, index) => index % step === 0),
+    step
+  };
+}
+
+/**
+ * Estimates the samples per arm needed to detect the observed difference
+ * @param {number[]} control - Per-interval samples of the control arm
+ * @param {number[]} test - Per-interval samples of the test arm
+ * @param {number} alpha - Significance level (0.01, 0.05 or 0.1)
+ * @param {number} power - Statistical power (0.8 or 0.9)
+ * @returns {number|null} Samples per arm, or null when there is no difference to detect
+ */
+function requiredSampleSize(control, test, alpha = 0.05, power = 0.8) {
+  const a = summarize(control);
+  const b = summarize(test);
+  const difference = Math.abs(b.mean - a.mean);
+
+  if (difference === 0) {
+    return null;
+  }
+
+  const zAlpha = Z_VALUES[alpha] || Z_VALUES[0.05];
+  const zPower = POWER_Z_VALUES[power] || POWER_Z_VALUES[0.8];
+
+  return Math.max(2, Math.ceil(
+    (zAlpha + zPower) ** 2 * (a.variance + b.variance) / difference ** 2
+  ));
+}
+
+module.exports = {
+  summarize,
+  welchTTest,
+  tTestPValue,
+  requiredSampleSize,
+  thinOverlapping
+};
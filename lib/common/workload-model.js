@@ -0,0 +1,188 @@
+/**
+ * Seedable synthetic process population for experiment workloads
+ */
+
+// Executable names per classification, so filters and scoring see realistic processes
+const PROCESS_NAMES = {
+  critical: ['postgres', 'mysqld', 'nginx', 'java', 'redis-server'],
+  important: ['node', 'python3', 'php-fpm', 'sidekiq', 'gunicorn'],
+  standard: ['cron', 'sshd', 'bash', 'rsyslogd', 'systemd-journald']
+};
+
+// Patterns that burst often (15% per tick) unless they set burst_probability; others default to 2%
+const BURSTY_PATTERNS = ['spiky', 'variable', 'burst'];
+
+const DEFAULT_PATTERNS = [
+  { name: 'steady', percentage: 60, cpu_range: [5, 15], memory_range: [100, 500] },
+  { name: 'spiky', percentage: 30, cpu_range: [10, 80], memory_range: [200, 2000] },
+  { name: 'idle', percentage: 10, cpu_range: [0, 5], memory_range: [50, 100] }
+];
+
+/**
+ * Deterministic PRNG (mulberry32)
+ * @param {number} seed - Integer seed
+ * @returns {function(): number} Generator of floats in [0, 1)
+ */
+function createRandom(seed) {
+  let state = seed >>> 0;
+  return function random() {
+    state = (state + 0x6D2B79F5) >>> 0;
+    let t = state;
+    t = Math.imul(t ^ (t >>> 15), t | 1);
+    t ^= t + Math.imul(t ^ (t >>> 7), t | 61);
+    return ((t ^ (t >>> 14)) >>> 0) / 4294967296;
+  };
+}
+
+/**
+ * Picks a key from a { key: weight } map
+ */
+function pickWeighted(random, weights) {
+  const entries = Object.entries(weights).filter(([, weight]) => weight > 0);
+  const total = entries.reduce((sum, [, weight]) => sum + weight, 0);
+  let target = random() * total;
+
+  for (const [key, weight] of entries) {
+    target -= weight;
+    if (target < 0) return key;
+  }
+
+  return entries[entries.length - 1][0];
+}
+
+class WorkloadModel {
+  /**
+   * @param {Object} config - Experiment `workload` section
+   * @param {Object} options
+   * @param {number} options.seed - Seed for reproducible populations
+   */
+  constructor(config = {}, options = {}) {
+    const processes = config.processes || {};
+
+    this.seed = options.seed ?? config.seed ?? 1;
+    this.random = createRandom(this.seed);
+    this.hosts = config.hosts || 1;
+    this.targetCount = processes.total_count || 100;
+    this.churnRate = config.churn_rate ?? 0.02;
+    this.distribution = processes.distribution || { critical: 20, important: 30, standard: 50 };
+    this.patterns = processes.activity_patterns || DEFAULT_PATTERNS;
+
+    this.nextPid = 1000;
+    this.tickCount = 0;
+    this.population = [];
+
+    for (let i = 0; i < this.targetCount; i++) {
+      this.population.push(this.spawn());
+    }
+  }
+
+  spawn() {
+    const classification = pickWeighted(this.random, this.distribution);
+    const pattern = this.patterns[Number(pickWeighted(
+      this.random,
+      Object.fromEntries(this.patterns.map((p, index) => [index, p.percentage]))
+    ))];
+    const names = PROCESS_NAMES[classification] || PROCESS_NAMES.standard;
+    const [cpuMin, cpuMax] = pattern.cpu_range;
+    const [memMin, memMax] = pattern.memory_range;
+
+    return {
+      pid: this.nextPid++,
+      name: names[Math.floor(this.random() * names.length)],
+      classification,
+      pattern: pattern.name,
+      host: `loadgen-host-${Math.floor(this.random() * this.hosts) + 1}`,
+      cpuBase: cpuMin + (cpuMax - cpuMin) * this.random() * 0.5,
+      cpuMax,
+      // Cubing a uniform sample skews memory toward the minimum with a long tail of large processes
+      memoryMb: memMin + (memMax - memMin) * Math.pow(this.random(), 3),
+      burstProbability: pattern.burst_probability ??
+        (BURSTY_PATTERNS.includes(pattern.name) ? 0.15 : 0.02),
+      burstTicks: 0
+    };
+  }
+
+  /**
+   * Advance the population by one interval
+   * @returns {{tick: number, births: number, deaths: number, samples: Object[]}}
+   */
+  tick() {
+    this.tickCount++;
+
+    const survivors = this.population.filter(() => this.random() >= this.churnRate);
+    const deaths = this.population.length - survivors.length;
+
+    while (survivors.length < this.targetCount) {
+      survivors.push(this.spawn());
+    }
+    const births = survivors.length - (this.population.length - deaths);
+    this.population = survivors;
+
+    const samples = this.population.map(proc => {
+      if (proc.burstTicks > 0) {
+        proc.burstTicks--;
+      } else if (this.random() < proc.burstProbability) {
+        proc.burstTicks = 1 + Math.floor(this.random() * 3);
+      }
+
+      const cpuPercent = proc.burstTicks > 0
+        ? proc.cpuMax * (0.7 + 0.3 * this.random())
+        : proc.cpuBase * (0.8 + 0.4 * this.random());
+
+      return {
+        pid: proc.pid,
+        name: proc.name,
+        classification: proc.classification,
+        host: proc.host,
+        cpuPercent,
+        memoryBytes: Math.round(proc.memoryMb * (0.95 + 0.1 * this.random()) * 1024 * 1024)
+      };
+    });
+
+    return { tick: this.tickCount, births, deaths, samples };
+  }
+
+  /**
+   * Build an OTLP/HTTP JSON metrics payload for one tick
+   * @param {Object} tick - Result of tick()
+   * @param {number} timestamp - Milliseconds since epoch
+   */
+  toOtlp(tick, timestamp = Date.now()) {
+    const timeUnixNano = (BigInt(timestamp) * 1000000n).toString();
+    const stringAttribute = (key, value) => ({ key, value: { stringValue: String(value) } });
+
+    return {
+      resourceMetrics: tick.samples.map(sample => ({
+        resource: {
+          attributes: [
+            stringAttribute('service.name', 'nrdot-workload'),
+            stringAttribute('host.name', sample.host),
+            stringAttribute('process.pid', sample.pid),
+            stringAttribute('process.executable.name', sample.name),
+            stringAttribute('process.classification', sample.classification)
+          ]
+        },
+        scopeMetrics: [{
+          scope: { name: 'nrdot-workload-generator' },
+          metrics: [
+            {
+              name: 'process.cpu.utilization',
+              unit: '1',
+              gauge: { dataPoints: [{ asDouble: sample.cpuPercent / 100, timeUnixNano }] }
+            },
+            {
+              name: 'process.memory.usage',
+              unit: 'By',
+              gauge: { dataPoints: [{ asInt: String(sample.memoryBytes), timeUnixNano }] }
+            }
+          ]
+        }]
+      }))
+    };
+  }
+}
+
+module.exports = {
+  WorkloadModel,
+  createRandom
+};
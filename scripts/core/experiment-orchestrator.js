@@ -9,12 +9,14 @@ const { EventEmitter } = require('events');
 const yaml = require('js-yaml');
 const fs = require('fs').promises;
 const path = require('path');
+const readline = require('readline');
 const chalk = require('chalk');
 const ora = require('ora');
-const { exec } = require('child_process');
+const { exec, spawn } = require('child_process');
 const { promisify } = require('util');
-const { services } = require('../../lib/shared/index.js');
+const { getConfig, getApiClient } = require('../../lib/shared/index.js');
 const { logger } = require('../../scripts/src/utils/logger.js');
+const { welchTTest, requiredSampleSize, thinOverlapping } = require('../../lib/common/statistics.js');
 
 const execAsync = promisify(exec);
 
@@ -160,7 +162,8 @@ class ExperimentOrchestrator extends EventEmitter {
     
     const configMount = `-v ${path.resolve(`./configs/collector-profiles/${config.config_profile}.yaml`)}:/etc/otel/config.yaml`;
     
-    const command = `docker run -d --name ${containerName} ${envVars} ${configMount} ${config.image}`;
+    // Publish the OTLP/HTTP receiver on a free host port so the workload generator can reach every arm
+    const command = `docker run -d --name ${containerName} -p 4318 ${envVars} ${configMount} ${config.image}`;
     
     logger.info(`Launching container: ${command}`);
     
@@ -294,6 +297,8 @@ class ExperimentOrchestrator extends EventEmitter {
    */
   async collectMetrics(experiment) {
     const metrics = {};
+    const accountId = getConfig().requireAccountId();
+    const client = getApiClient();
     
     for (const [containerName, container] of this.containers) {
       metrics[containerName] = {};
@@ -307,11 +312,12 @@ class ExperimentOrchestrator extends EventEmitter {
             `WHERE containerName = '${containerName}' AND`
           );
           
-          const result = await services.nrql.runQuery(containerQuery);
-          metrics[containerName][metric.name] = {
-            value: result.results?.[0]?.value || 0,
-            unit: metric.unit
-          };
+          const result = await client.nrql(accountId, containerQuery);
+          const value = this.extractMetricValue(result.results?.[0]);
+          
+          metrics[containerName][metric.name] = value === null
+            ? { value: 0, unit: metric.unit, error: true }
+            : { value, unit: metric.unit };
         } catch (error) {
           logger.error(`Failed to collect metric ${metric.name}: ${error.message}`);
           metrics[containerName][metric.name] = { value: 0, unit: metric.unit, error: true };
@@ -322,6 +328,22 @@ class ExperimentOrchestrator extends EventEmitter {
     return metrics;
   }
 
+  /**
+   * First numeric field of an NRQL result row (e.g. `latest.x`, `uniqueCount.process.name`)
+   */
+  extractMetricValue(row) {
+    if (!row) {
+      return null;
+    }
+    
+    const value = Object.entries(row)
+      .filter(([key]) => !['beginTimeSeconds', 'endTimeSeconds', 'facet'].includes(key))
+      .map(([, fieldValue]) => fieldValue)
+      .find(fieldValue => typeof fieldValue === 'number' && Number.isFinite(fieldValue));
+    
+    return value === undefined ? null : value;
+  }
+
   /**
    * Aggregate metrics from raw data
    */
@@ -359,7 +381,8 @@ class ExperimentOrchestrator extends EventEmitter {
             avg: values.reduce((a, b) => a + b, 0) / values.length,
             min: Math.min(...values),
             max: Math.max(...values),
-            count: values.length
+            count: values.length,
+            values
           };
         }
       }
@@ -416,7 +439,12 @@ class ExperimentOrchestrator extends EventEmitter {
         const comparison = this.compareMetrics(
           controlMetrics,
           testMetrics,
-          experiment.comparison.dimensions
+          experiment.comparison.dimensions,
+          {
+            alpha: experiment.comparison.significance_level,
+            intervalSeconds: experiment.metrics.collection_interval_seconds,
+            windows: this.getQueryWindows(experiment.metrics)
+          }
         );
         
         analysis.comparisons[testGroup.name] = comparison;
@@ -431,6 +459,10 @@ class ExperimentOrchestrator extends EventEmitter {
         analysis.insights.push(...insights);
       }
       
+      // Build KPI table across all arms and pick a winner
+      analysis.kpi_table = this.buildKPITable(experiment, results.metrics);
+      analysis.winner = this.selectWinner(analysis);
+      
       // Generate recommendations
       analysis.recommendations = this.generateRecommendations(analysis);
       
@@ -450,7 +482,7 @@ class ExperimentOrchestrator extends EventEmitter {
   /**
    * Compare metrics between control and test
    */
-  compareMetrics(controlMetrics, testMetrics, dimensions) {
+  compareMetrics(controlMetrics, testMetrics, dimensions, options = {}) {
     const comparison = {};
     
     for (const dimension of dimensions) {
@@ -479,11 +511,81 @@ class ExperimentOrchestrator extends EventEmitter {
           percentage_of_control: controlValue > 0 ? (testValue / controlValue) * 100 : 100
         };
       }
+      
+      if (comparison[dimension.name]) {
+        comparison[dimension.name].significance = this.testSignificance(
+          controlMetrics[dimension.primary_metric]?.values || [],
+          testMetrics[dimension.primary_metric]?.values || [],
+          { ...options, windowSeconds: options.windows?.[dimension.primary_metric] }
+        );
+      }
     }
     
     return comparison;
   }
 
+  /**
+   * Test whether the difference between per-interval samples is significant
+   */
+  testSignificance(controlValues, testValues, options = {}) {
+    const { alpha = 0.05, intervalSeconds = 30 } = options;
+    const windowSeconds = options.windowSeconds || intervalSeconds;
+    
+    // Overlapping query windows make consecutive samples correlated; test independent ones only
+    const control = thinOverlapping(controlValues, windowSeconds, intervalSeconds);
+    const test = thinOverlapping(testValues, windowSeconds, intervalSeconds);
+    const effectiveInterval = intervalSeconds * control.step;
+    
+    const result = welchTTest(control.values, test.values, alpha);
+    if (!result) {
+      return null;
+    }
+    
+    const samplesNeeded = requiredSampleSize(control.values, test.values, alpha);
+    
+    return {
+      test: 'welch_t_test',
+      alpha,
+      p_value: result.pValue,
+      significant: result.significant,
+      samples: Math.min(control.values.length, test.values.length),
+      sample_interval_seconds: effectiveInterval,
+      samples_needed: samplesNeeded,
+      minimum_test_minutes: samplesNeeded !== null
+        ? Math.ceil(samplesNeeded * effectiveInterval / 60)
+        : null
+    };
+  }
+
+  /**
+   * Time window (seconds) each metric query aggregates over, from its SINCE clause.
+   * NRQL defaults to the last hour without SINCE; latest()-only queries are point-in-time.
+   */
+  getQueryWindows(metricsConfig) {
+    const units = { second: 1, minute: 60, hour: 3600, day: 86400 };
+    const windows = {};
+    
+    for (const metric of [...(metricsConfig.primary_metrics || []), ...(metricsConfig.secondary_metrics || [])]) {
+      if (!metric.query) continue;
+      
+      const match = metric.query.match(/\bSINCE\s+(\d+)\s+(second|minute|hour|day)s?\s+ago\b/i);
+      if (match) {
+        windows[metric.name] = parseInt(match[1], 10) * units[match[2].toLowerCase()];
+        continue;
+      }
+      
+      const selected = metric.query.match(/^\s*SELECT\s+([\s\S]*?)\s+FROM\s/i)?.[1] || '';
+      const functions = [...selected.matchAll(/\b(\w+)\s*\(/g)].map(fn => fn[1].toLowerCase());
+      const latestOnly = functions.length > 0 && functions.every(fn => fn === 'latest');
+      
+      if (!latestOnly) {
+        windows[metric.name] = 3600;
+      }
+    }
+    
+    return windows;
+  }
+
   /**
    * Generate insights from comparison
    */
@@ -506,6 +608,19 @@ class ExperimentOrchestrator extends EventEmitter {
       });
     }
     
+    // Flag differences that could be noise
+    for (const [dimension, data] of Object.entries(comparison)) {
+      if (data.significance && !data.significance.significant) {
+        const duration = data.significance.minimum_test_minutes
+          ? `; run at least ${data.significance.minimum_test_minutes} minutes to confirm`
+          : '';
+        insights.push({
+          type: 'warning',
+          message: `${testGroupName} ${dimension} difference is not statistically significant (p=${data.significance.p_value.toFixed(3)})${duration}`
+        });
+      }
+    }
+    
     // Check coverage
     if (comparison.coverage_maintained) {
       const coveragePercentage = comparison.coverage_maintained.percentage_of_control;
@@ -531,29 +646,36 @@ class ExperimentOrchestrator extends EventEmitter {
   generateRecommendations(analysis) {
     const recommendations = [];
     
-    // Find best performing configuration
-    let bestConfig = null;
-    let bestSavings = 0;
-    
-    for (const [configName, comparison] of Object.entries(analysis.comparisons)) {
-      const savings = Math.abs(comparison.cost_reduction?.change_percentage || 0);
-      const coverageMaintained = comparison.coverage_maintained?.percentage_of_control >= 95;
-      
-      if (savings > bestSavings && coverageMaintained) {
-        bestConfig = configName;
-        bestSavings = savings;
-      }
-    }
+    // Recommend the winning configuration
+    const winner = analysis.winner || this.selectWinner(analysis);
     
-    if (bestConfig) {
+    if (winner.arm) {
       recommendations.push({
         priority: 'high',
-        action: `Deploy ${bestConfig} configuration`,
-        rationale: `Achieves ${bestSavings.toFixed(1)}% cost reduction while maintaining coverage`,
+        action: `Deploy ${winner.arm} configuration`,
+        rationale: winner.rationale,
         impact: 'immediate'
       });
     }
     
+    // Recommend a longer run when a decision-driving change could be noise;
+    // coverage differences only gate the winner and are expected to be small
+    const requiredMinutes = Object.values(analysis.comparisons)
+      .flatMap(comparison => Object.values(comparison))
+      .filter(data => data.change_percentage !== undefined)
+      .filter(data => data.significance && !data.significance.significant)
+      .map(data => data.significance.minimum_test_minutes)
+      .filter(minutes => minutes !== null);
+    
+    if (requiredMinutes.length > 0) {
+      recommendations.push({
+        priority: 'medium',
+        action: `Extend the test phase to at least ${Math.max(...requiredMinutes)} minutes`,
+        rationale: 'Some observed differences are not statistically significant at the current duration',
+        impact: 'next run'
+      });
+    }
+    
     // Add specific recommendations based on patterns
     const hasHighReduction = Object.values(analysis.comparisons).some(
       comp => Math.abs(comp.data_reduction?.change_percentage || 0) > 50
@@ -571,24 +693,152 @@ class ExperimentOrchestrator extends EventEmitter {
     return recommendations;
   }
 
+  /**
+   * Build a KPI table with every arm's primary metrics and deltas to control
+   */
+  buildKPITable(experiment, metrics) {
+    const controlName = `${experiment.id}-${experiment.containers.control.name}`;
+    const controlMetrics = metrics[controlName] || {};
+    
+    const arms = [
+      { name: experiment.containers.control.name, role: 'control' },
+      ...experiment.containers.test_groups.map(group => ({ name: group.name, role: 'test' }))
+    ];
+    
+    return arms.map(arm => {
+      const armMetrics = metrics[`${experiment.id}-${arm.name}`] || {};
+      const kpis = {};
+      
+      for (const metric of experiment.metrics.primary_metrics) {
+        const value = armMetrics[metric.name]?.avg;
+        const controlValue = controlMetrics[metric.name]?.avg;
+        
+        kpis[metric.name] = {
+          value: value ?? null,
+          unit: metric.unit,
+          delta_percentage: value !== undefined && controlValue > 0
+            ? ((value - controlValue) / controlValue) * 100
+            : null
+        };
+      }
+      
+      return { arm: arm.name, role: arm.role, kpis };
+    });
+  }
+
+  /**
+   * Pick the test group with the largest cost reduction that keeps coverage
+   */
+  selectWinner(analysis) {
+    let winner = null;
+    let bestSavings = 0;
+    
+    let winnerSignificance = null;
+    
+    for (const [configName, comparison] of Object.entries(analysis.comparisons)) {
+      const costDimension = Object.entries(comparison)
+        .find(([name, data]) => name.includes('cost') && data.change_percentage !== undefined)?.[1];
+      const coverageDimension = Object.entries(comparison)
+        .find(([name]) => name.includes('coverage'))?.[1];
+      
+      // A cost reduction shows up as a negative change against control
+      const savings = costDimension ? -costDimension.change_percentage : 0;
+      const coverageMaintained = coverageDimension?.percentage_of_control >= 95;
+      
+      if (savings > bestSavings && coverageMaintained) {
+        winner = configName;
+        bestSavings = savings;
+        winnerSignificance = costDimension.significance || null;
+      }
+    }
+    
+    if (!winner) {
+      return {
+        arm: null,
+        cost_reduction_percentage: 0,
+        rationale: 'No test group reduced cost while maintaining at least 95% of control coverage; keep the control configuration'
+      };
+    }
+    
+    let rationale = `Achieves ${bestSavings.toFixed(1)}% cost reduction while maintaining coverage`;
+    if (winnerSignificance) {
+      rationale += winnerSignificance.significant
+        ? ` (significant, p=${winnerSignificance.p_value.toFixed(3)})`
+        : ` (not statistically significant, p=${winnerSignificance.p_value.toFixed(3)})`;
+    }
+    
+    return {
+      arm: winner,
+      cost_reduction_percentage: bestSavings,
+      significant: winnerSignificance ? winnerSignificance.significant : null,
+      rationale
+    };
+  }
+
   /**
    * Save analysis results
    */
   async saveAnalysis(experiment, analysis) {
     const resultsDir = path.join('./experiment-results', experiment.id);
     
-    // Save JSON report
-    await fs.writeFile(
-      path.join(resultsDir, 'analysis.json'),
-      JSON.stringify(analysis, null, 2)
-    );
-    
     // Generate markdown report
     const markdownReport = this.generateMarkdownReport(experiment, analysis);
     await fs.writeFile(
       path.join(resultsDir, 'report.md'),
       markdownReport
     );
+    
+    // Publish report as a New Relic dashboard if requested
+    const dashboardReport = experiment.output?.reports?.find(report => report.type === 'dashboard');
+    let publishError = null;
+    if (dashboardReport) {
+      try {
+        analysis.dashboard = await this.publishReportDashboard(experiment, markdownReport, dashboardReport);
+        logger.info(`Published experiment report dashboard: ${analysis.dashboard.guid}`);
+      } catch (error) {
+        publishError = error;
+        analysis.dashboard = { error: error.message };
+        logger.error(`Failed to publish experiment report dashboard: ${error.message}`);
+      }
+    }
+    
+    // Save JSON report
+    await fs.writeFile(
+      path.join(resultsDir, 'analysis.json'),
+      JSON.stringify(analysis, null, 2)
+    );
+    
+    // Reports are on disk; still fail the run so a requested dashboard is not silently missing
+    if (publishError) {
+      throw new Error(`Report saved to ${resultsDir} but dashboard publishing failed: ${publishError.message}`);
+    }
+  }
+
+  /**
+   * Publish the markdown report as a New Relic dashboard page
+   */
+  async publishReportDashboard(experiment, markdownReport, reportConfig) {
+    const accountId = getConfig().requireAccountId();
+    const name = (reportConfig.dashboard_name || 'NRDOT Experiment ${experiment.id}')
+      .replace('${experiment.id}', experiment.id);
+    
+    const dashboard = {
+      name,
+      description: experiment.description,
+      permissions: 'PUBLIC_READ_WRITE',
+      pages: [{
+        name: 'Report',
+        widgets: [{
+          title: experiment.name,
+          visualization: { id: 'viz.markdown' },
+          layout: { column: 1, row: 1, width: 12, height: 12 },
+          rawConfiguration: { text: markdownReport }
+        }]
+      }]
+    };
+    
+    // Markdown widgets carry no NRQL, so create directly instead of via DashboardService validation
+    return getApiClient().createDashboard(accountId, dashboard);
   }
 
   /**
@@ -608,18 +858,50 @@ class ExperimentOrchestrator extends EventEmitter {
     for (const [configName, comparison] of Object.entries(analysis.comparisons)) {
       report += `### ${configName}\n\n`;
       
-      report += `| Metric | Control | Test | Change |\n`;
-      report += `|--------|---------|------|--------|\n`;
+      report += `| Metric | Control | Test | Change | p-value | Significant |\n`;
+      report += `|--------|---------|------|--------|---------|-------------|\n`;
       
       for (const [metric, data] of Object.entries(comparison)) {
+        let change;
         if (data.change_percentage !== undefined) {
-          report += `| ${metric} | ${data.control.toFixed(2)} | ${data.test.toFixed(2)} | ${data.change_percentage.toFixed(1)}% |\n`;
+          change = `${data.change_percentage.toFixed(1)}%`;
+        } else if (data.difference !== undefined) {
+          change = `${data.difference >= 0 ? '+' : ''}${data.difference.toFixed(2)} (${data.percentage_of_control.toFixed(1)}% of control)`;
+        } else {
+          continue;
         }
+        
+        const pValue = data.significance ? data.significance.p_value.toFixed(3) : 'n/a';
+        const significant = data.significance ? (data.significance.significant ? 'yes' : 'no') : 'n/a';
+        report += `| ${metric} | ${data.control.toFixed(2)} | ${data.test.toFixed(2)} | ${change} | ${pValue} | ${significant} |\n`;
       }
       
       report += '\n';
     }
     
+    report += `## KPI Comparison\n\n`;
+    
+    const kpiNames = experiment.metrics.primary_metrics.map(metric => metric.name);
+    report += `| Arm | Role | ${kpiNames.join(' | ')} |\n`;
+    report += `|-----|------|${kpiNames.map(() => '------').join('|')}|\n`;
+    
+    for (const row of analysis.kpi_table || []) {
+      const cells = kpiNames.map(name => {
+        const kpi = row.kpis[name];
+        if (kpi.value === null) return 'n/a';
+        if (row.role === 'control' || kpi.delta_percentage === null) return kpi.value.toFixed(2);
+        return `${kpi.value.toFixed(2)} (${kpi.delta_percentage >= 0 ? '+' : ''}${kpi.delta_percentage.toFixed(1)}%)`;
+      });
+      report += `| ${row.arm} | ${row.role} | ${cells.join(' | ')} |\n`;
+    }
+    
+    report += '\n';
+    
+    if (analysis.winner) {
+      report += `## Winner\n\n`;
+      report += `**${analysis.winner.arm || experiment.containers.control.name}**: ${analysis.winner.rationale}\n\n`;
+    }
+    
     report += `## Insights\n\n`;
     for (const insight of analysis.insights) {
       report += `- **${insight.type.toUpperCase()}**: ${insight.message}\n`;
@@ -662,11 +944,55 @@ class ExperimentOrchestrator extends EventEmitter {
    * Start workload generator
    */
   async startWorkloadGenerator(workloadConfig) {
-    // This would launch the metrics generator with specific config
-    logger.info('Starting workload generator');
+    // Every arm must receive the same ticks, otherwise the comparison is meaningless
+    const endpoints = [];
+    for (const container of this.containers.values()) {
+      endpoints.push(await this.getOtlpEndpoint(container));
+    }
+    
+    if (endpoints.length === 0) {
+      throw new Error('No experiment containers to send workload to');
+    }
+    
+    logger.info(`Starting workload generator -> ${endpoints.join(', ')}`);
+    
+    // Pass the config as a single argument; a shell would split the JSON
+    this.workloadProcess = spawn(
+      process.execPath,
+      [
+        'scripts/workload-generator.js',
+        '--config', JSON.stringify(workloadConfig),
+        '--endpoints', endpoints.join(',')
+      ],
+      { stdio: ['ignore', 'pipe', 'pipe'] }
+    );
+    
+    readline.createInterface({ input: this.workloadProcess.stdout })
+      .on('line', line => logger.info(`[workload] ${line}`));
+    readline.createInterface({ input: this.workloadProcess.stderr })
+      .on('line', line => logger.error(`[workload] ${line}`));
+    
+    this.workloadProcess.on('exit', (code, signal) => {
+      if (code) {
+        logger.error(`Workload generator exited with code ${code}`);
+      } else if (signal && signal !== 'SIGTERM') {
+        logger.error(`Workload generator killed by ${signal}`);
+      }
+    });
+  }
+
+  /**
+   * Host-reachable OTLP/HTTP endpoint of a running container
+   */
+  async getOtlpEndpoint(container) {
+    const { stdout } = await execAsync(`docker port ${container.id} 4318/tcp`);
+    const match = stdout.match(/:(\d+)\s*$/m);
+    
+    if (!match) {
+      throw new Error(`Container ${container.name} does not publish OTLP port 4318`);
+    }
     
-    const command = `node scripts/metrics-generator-fixed.js --config ${JSON.stringify(workloadConfig)}`;
-    this.workloadProcess = exec(command);
+    return `http://localhost:${match[1]}`;
   }
 
   /**
@@ -14,6 +14,7 @@ const { EntityCommand } = require('./commands/entity.js');
 const { IngestCommand } = require('./commands/ingest.js');
 const { LLMCommand } = require('./commands/llm.js');
 const { ExperimentCommand } = require('./commands/experiment.js');
+const { VerifyCommand } = require('./commands/verify.js');
 
 // Load environment variables
 dotenv.config();
@@ -49,6 +50,7 @@ async function main() {
   program.addCommand(new IngestCommand().getCommand());
   program.addCommand(new LLMCommand().getCommand());
   program.addCommand(new ExperimentCommand().getCommand());
+  program.addCommand(new VerifyCommand().getCommand());
 
   // Top-level commands
   program
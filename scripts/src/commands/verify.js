@@ -0,0 +1,115 @@
+const { Command } = require('commander');
+const { VerifyService, SELF_CHECK_EVENT_TYPE } = require('../services/verify.service.js');
+const { Config } = require('../core/config.js');
+const { Output } = require('../utils/output.js');
+const { ValidationError } = require('../utils/errors.js');
+
+class VerifyCommand {
+  getCommand() {
+    const verify = new Command('verify')
+      .description('Verify exported process data is arriving in NRDB (counts per ring, freshness)')
+      .option('--since <duration>', 'Time range', '10 minutes ago')
+      .option('--max-age <seconds>', 'Maximum age of the latest data point per ring', v => parseInt(v, 10), 300)
+      .option('--rings <rings>', 'Comma-separated rings that must report data')
+      .option('--metric-pattern <pattern>', 'metricName LIKE pattern for exported process data', 'process.%')
+      .option('--watch <seconds>', 'Repeat the check on an interval', v => parseInt(v, 10))
+      .option('--report', 'Record each check as a ' + SELF_CHECK_EVENT_TYPE + ' event')
+      .option('--license-key <key>', 'License key for self-check events (defaults to NEW_RELIC_LICENSE_KEY)')
+      .option('--account-id <id>', 'Override default account ID')
+      .action(async (options) => {
+        await this.verify(options, verify.parent.opts());
+      });
+
+    return verify;
+  }
+
+  async verify(options, globalOptions) {
+    const config = new Config({ ...globalOptions, ...options });
+    const output = new Output(config.outputFormat, config.quiet);
+    const service = new VerifyService(config);
+
+    const checkOptions = {
+      since: options.since || '10 minutes ago',
+      maxAgeSeconds: options.maxAge ?? 300,
+      metricPattern: options.metricPattern,
+      expectedRings: options.rings ? options.rings.split(',').map(r => r.trim()).filter(Boolean) : []
+    };
+    const licenseKey = options.licenseKey || process.env.NEW_RELIC_LICENSE_KEY;
+
+    try {
+      config.requireAccountId();
+
+      if (options.report && !licenseKey) {
+        throw new ValidationError(
+          'License key not found. Set NEW_RELIC_LICENSE_KEY environment variable or use --license-key flag'
+        );
+      }
+
+      if (!options.watch) {
+        const verification = await this.runCheck(service, output, checkOptions, options.report && licenseKey);
+        if (!verification.healthy) {
+          process.exit(1);
+        }
+        return;
+      }
+
+      output.info(`Verifying every ${options.watch}s (Ctrl+C to stop)`);
+
+      // Periodic self-check: keep going through failures so ingest gaps show up as events
+      while (true) {
+        try {
+          await this.runCheck(service, output, checkOptions, options.report && licenseKey);
+        } catch (error) {
+          output.error(error.message, error);
+        }
+        await new Promise(resolve => setTimeout(resolve, options.watch * 1000));
+      }
+    } catch (error) {
+      output.stopSpinner(false, 'Failed to verify data');
+      output.error(error.message, error);
+      process.exit(1);
+    }
+  }
+
+  async runCheck(service, output, checkOptions, licenseKey) {
+    output.startSpinner('Querying NRDB...');
+    let verification;
+    try {
+      verification = await service.verifyRings(checkOptions);
+    } catch (error) {
+      output.stopSpinner(false, 'Failed to query NRDB');
+      throw error;
+    }
+    output.stopSpinner(verification.healthy);
+
+    if (output.format === 'json') {
+      output.print(verification);
+    } else {
+      output.print(verification.rings, {
+        title: `Ring Verification (${verification.checkedAt})`,
+        table: true,
+        columns: ['ring', 'dataPoints', 'hosts', 'lastSeen', 'ageSeconds', 'status']
+      });
+
+      if (verification.healthy) {
+        output.success(`All rings reporting (${output.formatNumber(verification.totalDataPoints)} data points)`);
+      } else {
+        output.warning('\nIssues:');
+        verification.issues.forEach(issue => {
+          output.warning(`  • ${issue}`);
+        });
+      }
+    }
+
+    if (licenseKey) {
+      const sent = await service.sendSelfCheckEvent(verification, licenseKey);
+      output.info(`Recorded ${sent} ${SELF_CHECK_EVENT_TYPE} events`);
+    }
+
+    return verification;
+  }
+}
+
+module.exports = {
+  VerifyCommand
+};
@@ -0,0 +1,146 @@
+const { NerdGraphClient } = require('../core/api-client.js');
+const { logger } = require('../utils/logger.js');
+const { APIError } = require('../utils/errors.js');
+
+// Self-check events are written here so ingest verification can be charted and alerted on
+const SELF_CHECK_EVENT_TYPE = 'NrdotVerification';
+
+class VerifyService {
+  constructor(config) {
+    this.config = config;
+    this.client = new NerdGraphClient(config);
+  }
+
+  /**
+   * Verify exported process data is arriving in NRDB for every ring
+   * @param {Object} options
+   * @param {string} options.since - NRQL time window
+   * @param {number} options.maxAgeSeconds - Oldest acceptable data point per ring
+   * @param {string[]} options.expectedRings - Rings that must be present
+   * @param {string} options.metricPattern - metricName LIKE pattern for the exported data
+   */
+  async verifyRings(options = {}) {
+    const accountId = this.config.requireAccountId();
+    const since = options.since || '10 minutes ago';
+    const metricPattern = (options.metricPattern || 'process.%').replace(/'/g, "\\'");
+
+    const query = `SELECT count(*) AS dataPoints, uniqueCount(host.name) AS hosts, latest(timestamp) AS lastSeen FROM Metric WHERE metricName LIKE '${metricPattern}' AND nrdot.ring IS NOT NULL FACET nrdot.ring SINCE ${since} LIMIT 100`;
+
+    const result = await this.client.nrql(accountId, query);
+
+    return this.evaluate(result.results || [], {
+      ...options,
+      since,
+      now: Date.now()
+    });
+  }
+
+  evaluate(results, options = {}) {
+    const maxAgeSeconds = options.maxAgeSeconds ?? 300;
+    const now = options.now || Date.now();
+    const issues = [];
+
+    const rings = results.map(row => {
+      const ring = String(Array.isArray(row.facet) ? row.facet[0] : row.facet);
+      const lastSeen = row.lastSeen || 0;
+      const ageSeconds = lastSeen ? Math.max(0, Math.round((now - lastSeen) / 1000)) : null;
+      const fresh = ageSeconds !== null && ageSeconds <= maxAgeSeconds;
+
+      if (!fresh) {
+        issues.push(`Ring ${ring} is stale: last data point ${ageSeconds === null ? 'unknown' : `${ageSeconds}s ago`} (max ${maxAgeSeconds}s)`);
+      }
+
+      return {
+        ring,
+        dataPoints: row.dataPoints || 0,
+        hosts: row.hosts || 0,
+        lastSeen: lastSeen ? new Date(lastSeen).toISOString() : null,
+        ageSeconds,
+        status: fresh ? 'ok' : 'stale'
+      };
+    }).sort((a, b) => a.ring.localeCompare(b.ring, undefined, { numeric: true }));
+
+    const found = new Set(rings.map(r => r.ring));
+    (options.expectedRings || []).forEach(ring => {
+      if (!found.has(String(ring))) {
+        issues.push(`Ring ${ring} has no data since ${options.since || '10 minutes ago'}`);
+        rings.push({
+          ring: String(ring),
+          dataPoints: 0,
+          hosts: 0,
+          lastSeen: null,
+          ageSeconds: null,
+          status: 'missing'
+        });
+      }
+    });
+
+    if (rings.length === 0) {
+      issues.push(`No process data with nrdot.ring found since ${options.since || '10 minutes ago'}`);
+    }
+
+    return {
+      healthy: issues.length === 0,
+      checkedAt: new Date(now).toISOString(),
+      maxAgeSeconds,
+      totalDataPoints: rings.reduce((sum, r) => sum + r.dataPoints, 0),
+      rings,
+      issues
+    };
+  }
+
+  /**
+   * Record a verification result as a custom event via the Event API
+   * @param {Object} verification - Result of verifyRings()
+   * @param {string} licenseKey - Ingest license key
+   */
+  async sendSelfCheckEvent(verification, licenseKey) {
+    const accountId = this.config.requireAccountId();
+    const endpoint = this.config.region === 'EU'
+      ? `https://insights-collector.eu01.nr-data.net/v1/accounts/${accountId}/events`
+      : `https://insights-collector.newrelic.com/v1/accounts/${accountId}/events`;
+
+    // One event per ring keeps the attributes flat and facetable
+    const events = verification.rings.map(ring => ({
+      eventType: SELF_CHECK_EVENT_TYPE,
+      timestamp: Date.parse(verification.checkedAt),
+      'nrdot.ring': ring.ring,
+      status: ring.status,
+      dataPoints: ring.dataPoints,
+      hosts: ring.hosts,
+      ageSeconds: ring.ageSeconds,
+      healthy: verification.healthy
+    }));
+
+    if (events.length === 0) {
+      events.push({
+        eventType: SELF_CHECK_EVENT_TYPE,
+        timestamp: Date.parse(verification.checkedAt),
+        status: 'missing',
+        dataPoints: 0,
+        healthy: false
+      });
+    }
+
+    const response = await fetch(endpoint, {
+      method: 'POST',
+      headers: {
+        'Content-Type': 'application/json',
+        'Api-Key': licenseKey
+      },
+      body: JSON.stringify(events)
+    });
+
+    if (!response.ok) {
+      throw new APIError(`Event API request failed: ${response.status} ${response.statusText}`, response.status);
+    }
+
+    logger.debug(`Sent ${events.length} ${SELF_CHECK_EVENT_TYPE} events`);
+    return events.length;
+  }
+}
+
+module.exports = {
+  VerifyService,
+  SELF_CHECK_EVENT_TYPE
+};
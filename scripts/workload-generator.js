@@ -0,0 +1,104 @@
+#!/usr/bin/env node
+/**
+ * Synthetic Workload Generator
+ * Sends a seedable synthetic process population to one or more collectors' OTLP/HTTP receivers
+ *
+ * Usage: node scripts/workload-generator.js [--config <json>] [--seed <n>] [--interval <seconds>]
+ *                                           [--endpoints <url,url>] [--duration <seconds>]
+ */
+
+const { WorkloadModel } = require('../lib/common/workload-model.js');
+
+function parseArgs(argv) {
+    const args = {};
+    for (let i = 0; i < argv.length; i++) {
+        if (argv[i].startsWith('--')) {
+            args[argv[i].slice(2)] = argv[i + 1];
+            i++;
+        }
+    }
+    return args;
+}
+
+class WorkloadGenerator {
+    constructor(workloadConfig = {}, options = {}) {
+        const endpoints = options.endpoints ||
+            (workloadConfig.endpoints && workloadConfig.endpoints.join(',')) ||
+            process.env.WORKLOAD_OTLP_ENDPOINTS || 'http://localhost:4318';
+
+        this.config = {
+            // Each tick goes to every endpoint so all collectors see identical load
+            endpoints: endpoints.split(',').map(e => e.trim().replace(/\/$/, '')).filter(Boolean),
+            interval: parseInt(options.interval || workloadConfig.interval_seconds || '10') * 1000,
+            duration: options.duration ? parseInt(options.duration) * 1000 : null
+        };
+
+        this.model = new WorkloadModel(workloadConfig, {
+            seed: options.seed !== undefined ? parseInt(options.seed) : undefined
+        });
+        this.timer = null;
+    }
+
+    async sendTick() {
+        const tick = this.model.tick();
+        const payload = this.model.toOtlp(tick);
+
+        const body = JSON.stringify(payload);
+
+        await Promise.all(this.config.endpoints.map(async endpoint => {
+            try {
+                const response = await fetch(`${endpoint}/v1/metrics`, {
+                    method: 'POST',
+                    headers: { 'Content-Type': 'application/json' },
+                    body
+                });
+
+                if (!response.ok) {
+                    console.error(`OTLP export to ${endpoint} failed: ${response.status} ${response.statusText}`);
+                }
+            } catch (error) {
+                console.error(`OTLP export to ${endpoint} failed:`, error.message);
+            }
+        }));
+
+        console.log(`[tick ${tick.tick}] ${tick.samples.length} processes, ${tick.births} births, ${tick.deaths} deaths`);
+    }
+
+    start() {
+        console.log(`Workload generator: ${this.model.targetCount} processes, seed ${this.model.seed}, ` +
+            `every ${this.config.interval / 1000}s -> ${this.config.endpoints.join(', ')}`);
+
+        this.sendTick();
+        this.timer = setInterval(() => this.sendTick(), this.config.interval);
+
+        if (this.config.duration) {
+            setTimeout(() => this.stop(), this.config.duration);
+        }
+    }
+
+    stop() {
+        if (this.timer) {
+            clearInterval(this.timer);
+            this.timer = null;
+        }
+    }
+}
+
+// Main execution
+if (require.main === module) {
+    const args = parseArgs(process.argv.slice(2));
+    const workloadConfig = args.config ? JSON.parse(args.config) : {};
+    const generator = new WorkloadGenerator(workloadConfig, args);
+
+    // Handle graceful shutdown
+    ['SIGTERM', 'SIGINT'].forEach(signal => {
+        process.on(signal, () => {
+            generator.stop();
+            process.exit(0);
+        });
+    });
+
+    generator.start();
+}
+
+module.exports = { WorkloadGenerator };
@@ -0,0 +1,326 @@
+const mockClient = {
+  nrql: jest.fn(),
+  createDashboard: jest.fn()
+};
+
+jest.mock('../../../../lib/shared/index.js', () => ({
+  getConfig: () => ({ requireAccountId: () => '12345' }),
+  getApiClient: () => mockClient
+}));
+
+const { ExperimentOrchestrator } = require('../../../../scripts/core/experiment-orchestrator');
+
+describe('ExperimentOrchestrator', () => {
+  let orchestrator;
+
+  const experiment = {
+    id: 'exp-001',
+    name: 'Cost Test',
+    description: 'Compare profiles',
+    containers: {
+      control: { name: 'control' },
+      test_groups: [{ name: 'balanced' }, { name: 'aggressive' }]
+    },
+    metrics: {
+      primary_metrics: [
+        { name: 'estimated_cost', unit: 'usd_per_hour' },
+        { name: 'process_count', unit: 'count' }
+      ]
+    }
+  };
+
+  beforeEach(() => {
+    orchestrator = new ExperimentOrchestrator();
+    mockClient.nrql = jest.fn();
+    mockClient.createDashboard = jest.fn();
+  });
+
+  describe('collected metrics', () => {
+    const collectedExperiment = {
+      ...experiment,
+      containers: {
+        control: { name: 'control' },
+        test_groups: [{ name: 'balanced' }]
+      },
+      metrics: {
+        collection_interval_seconds: 30,
+        primary_metrics: [
+          {
+            name: 'estimated_cost',
+            query: 'SELECT latest(nrdot.estimated.cost.hourly) FROM Metric WHERE service.name = \'nrdot\'',
+            unit: 'usd_per_hour'
+          },
+          {
+            name: 'process_count',
+            query: 'SELECT uniqueCount(process.executable.name) FROM Metric WHERE service.name = \'nrdot\'',
+            unit: 'count'
+          }
+        ]
+      },
+      comparison: {
+        significance_level: 0.05,
+        dimensions: [
+          { name: 'cost_reduction', primary_metric: 'estimated_cost', calculation: 'percentage_change' },
+          { name: 'coverage_maintained', primary_metric: 'process_count', calculation: 'absolute_difference' }
+        ]
+      }
+    };
+
+    const costs = {
+      'exp-001-control': [10, 11, 9, 10, 12, 10],
+      'exp-001-balanced': [7, 6, 8, 7, 6, 7]
+    };
+
+    test('should analyse values collected through the NerdGraph client', async () => {
+      let tick = 0;
+      mockClient.nrql = jest.fn(async (accountId, query) => {
+        const container = query.match(/containerName = '([^']+)'/)[1];
+        if (query.includes('latest(')) {
+          return { results: [{ 'latest.nrdot.estimated.cost.hourly': costs[container][tick] }] };
+        }
+        return { results: [{ beginTimeSeconds: 0, 'uniqueCount.process.executable.name': 100 }] };
+      });
+
+      orchestrator.containers = new Map([
+        ['exp-001-control', {}],
+        ['exp-001-balanced', {}]
+      ]);
+      orchestrator.saveAnalysis = jest.fn();
+
+      const rawData = [];
+      for (tick = 0; tick < costs['exp-001-control'].length; tick++) {
+        rawData.push({ timestamp: new Date(), metrics: await orchestrator.collectMetrics(collectedExperiment) });
+      }
+
+      expect(mockClient.nrql).toHaveBeenCalledWith(
+        '12345',
+        expect.stringContaining("WHERE containerName = 'exp-001-balanced' AND service.name")
+      );
+
+      const metrics = await orchestrator.aggregateMetrics(rawData);
+      const analysis = await orchestrator.analysisPhase(collectedExperiment, { metrics });
+
+      expect(metrics['exp-001-control'].estimated_cost.values).toEqual(costs['exp-001-control']);
+      expect(analysis.kpi_table[1].kpis.estimated_cost.value).toBeCloseTo(41 / 6);
+      expect(analysis.comparisons.balanced.cost_reduction.change_percentage).toBeLessThan(0);
+      expect(analysis.comparisons.balanced.coverage_maintained.percentage_of_control).toBe(100);
+      expect(analysis.winner.arm).toBe('balanced');
+      expect(orchestrator.saveAnalysis).toHaveBeenCalledWith(collectedExperiment, analysis);
+    });
+
+    test('should compute significance from the collected samples', async () => {
+      let tick = 0;
+      mockClient.nrql = jest.fn(async (accountId, query) => {
+        const container = query.match(/containerName = '([^']+)'/)[1];
+        if (query.includes('latest(')) {
+          return { results: [{ 'latest.nrdot.estimated.cost.hourly': costs[container][tick] }] };
+        }
+        return { results: [{ 'uniqueCount.process.executable.name': 100 + (tick % 2) }] };
+      });
+
+      orchestrator.containers = new Map([
+        ['exp-001-control', {}],
+        ['exp-001-balanced', {}]
+      ]);
+      orchestrator.saveAnalysis = jest.fn();
+
+      const rawData = [];
+      for (tick = 0; tick < costs['exp-001-control'].length; tick++) {
+        rawData.push({ timestamp: new Date(), metrics: await orchestrator.collectMetrics(collectedExperiment) });
+      }
+
+      const metrics = await orchestrator.aggregateMetrics(rawData);
+      const analysis = await orchestrator.analysisPhase(collectedExperiment, { metrics });
+      const costSignificance = analysis.comparisons.balanced.cost_reduction.significance;
+      const coverageSignificance = analysis.comparisons.balanced.coverage_maintained.significance;
+
+      // latest() is point-in-time, so every cost sample is kept
+      expect(costSignificance.samples).toBe(6);
+      expect(costSignificance.significant).toBe(true);
+      expect(analysis.winner.significant).toBe(true);
+
+      // uniqueCount() without SINCE spans the last hour, leaving too few independent samples
+      expect(coverageSignificance).toBeNull();
+
+      const report = orchestrator.generateMarkdownReport(collectedExperiment, analysis);
+      expect(report).toContain('| coverage_maintained | 100.50 | 100.50 | +0.00 (100.0% of control) | n/a | n/a |');
+    });
+
+    test('should record rows without a numeric field as errors', async () => {
+      mockClient.nrql.mockResolvedValue({ results: [{ facet: 'a' }] });
+      orchestrator.containers = new Map([['exp-001-control', {}]]);
+
+      const metrics = await orchestrator.collectMetrics(collectedExperiment);
+
+      expect(metrics['exp-001-control'].estimated_cost).toEqual({ value: 0, unit: 'usd_per_hour', error: true });
+    });
+  });
+
+  describe('buildKPITable', () => {
+    test('should list every arm with deltas against control', () => {
+      const table = orchestrator.buildKPITable(experiment, {
+        'exp-001-control': { estimated_cost: { avg: 10 }, process_count: { avg: 100 } },
+        'exp-001-balanced': { estimated_cost: { avg: 7 }, process_count: { avg: 98 } }
+      });
+
+      expect(table.map(row => row.arm)).toEqual(['control', 'balanced', 'aggressive']);
+      expect(table[0].role).toBe('control');
+      expect(table[0].kpis.estimated_cost.delta_percentage).toBe(0);
+      expect(table[1].kpis.estimated_cost.delta_percentage).toBeCloseTo(-30);
+      expect(table[1].kpis.estimated_cost.unit).toBe('usd_per_hour');
+      expect(table[2].kpis.process_count.value).toBeNull();
+      expect(table[2].kpis.process_count.delta_percentage).toBeNull();
+    });
+  });
+
+  describe('selectWinner', () => {
+    test('should pick the largest cost reduction that keeps coverage', () => {
+      const winner = orchestrator.selectWinner({
+        comparisons: {
+          balanced: {
+            cost_reduction: { change_percentage: -30 },
+            coverage_maintained: { percentage_of_control: 98 }
+          },
+          aggressive: {
+            cost_reduction: { change_percentage: -60 },
+            coverage_maintained: { percentage_of_control: 80 }
+          }
+        }
+      });
+
+      expect(winner.arm).toBe('balanced');
+      expect(winner.cost_reduction_percentage).toBe(30);
+    });
+
+    test('should include significance in the rationale', () => {
+      const winner = orchestrator.selectWinner({
+        comparisons: {
+          balanced: {
+            cost_reduction: { change_percentage: -30, significance: { significant: false, p_value: 0.2 } },
+            coverage_maintained: { percentage_of_control: 100 }
+          }
+        }
+      });
+
+      expect(winner.significant).toBe(false);
+      expect(winner.rationale).toContain('not statistically significant, p=0.200');
+    });
+
+    test('should not pick a winner without a coverage dimension', () => {
+      const winner = orchestrator.selectWinner({
+        comparisons: {
+          balanced: { cost_reduction: { change_percentage: -30 } }
+        }
+      });
+
+      expect(winner.arm).toBeNull();
+      expect(winner.cost_reduction_percentage).toBe(0);
+    });
+  });
+
+  describe('testSignificance', () => {
+    test('should only test samples from non-overlapping query windows', () => {
+      const control = [10, 11, 9, 10, 12, 10, 11, 9, 10, 12];
+      const test = [8, 7, 9, 8, 7, 8, 9, 7, 8, 8];
+
+      const overlapping = orchestrator.testSignificance(control, test, { intervalSeconds: 30, windowSeconds: 60 });
+      const independent = orchestrator.testSignificance(control, test, { intervalSeconds: 30 });
+
+      expect(overlapping.samples).toBe(5);
+      expect(overlapping.sample_interval_seconds).toBe(60);
+      expect(independent.samples).toBe(10);
+      expect(overlapping.p_value).toBeGreaterThan(independent.p_value);
+    });
+
+    test('getQueryWindows should read SINCE clauses and default to the last hour', () => {
+      expect(orchestrator.getQueryWindows({
+        primary_metrics: [
+          { name: 'volume', query: 'SELECT sum(x) FROM Metric SINCE 1 minute ago' },
+          { name: 'cost', query: 'SELECT latest(y) FROM Metric' },
+          { name: 'processes', query: 'SELECT uniqueCount(z) FROM Metric' }
+        ],
+        secondary_metrics: [
+          { name: 'errors', query: 'SELECT count(*) FROM Log SINCE 5 minutes ago' }
+        ]
+      })).toEqual({ volume: 60, processes: 3600, errors: 300 });
+    });
+  });
+
+  describe('generateRecommendations', () => {
+    test('should only ask for a longer run when a percentage change is not significant', () => {
+      const notSignificant = { significant: false, p_value: 0.4, minimum_test_minutes: 90 };
+
+      const coverageOnly = orchestrator.generateRecommendations({
+        comparisons: {
+          balanced: {
+            cost_reduction: { change_percentage: -30, significance: { significant: true, p_value: 0.01, minimum_test_minutes: 5 } },
+            coverage_maintained: { percentage_of_control: 99, difference: -1, significance: notSignificant }
+          }
+        }
+      });
+      const costNoise = orchestrator.generateRecommendations({
+        comparisons: {
+          balanced: {
+            cost_reduction: { change_percentage: -3, significance: notSignificant },
+            coverage_maintained: { percentage_of_control: 99, difference: -1 }
+          }
+        }
+      });
+
+      expect(coverageOnly.map(rec => rec.action)).toEqual(['Deploy balanced configuration']);
+      expect(costNoise.map(rec => rec.action)).toContain('Extend the test phase to at least 90 minutes');
+    });
+  });
+
+  describe('generateMarkdownReport', () => {
+    test('should show each arm role in its own column', () => {
+      const report = orchestrator.generateMarkdownReport(experiment, {
+        timestamp: new Date('2024-01-01T00:00:00Z'),
+        comparisons: {},
+        kpi_table: orchestrator.buildKPITable(experiment, {
+          'exp-001-control': { estimated_cost: { avg: 10 }, process_count: { avg: 100 } },
+          'exp-001-balanced': { estimated_cost: { avg: 7 }, process_count: { avg: 98 } }
+        }),
+        insights: [],
+        recommendations: []
+      });
+
+      expect(report).toContain('| control | control | 10.00 | 100.00 |');
+      expect(report).toContain('| balanced | test | 7.00 (-30.0%) | 98.00 (-2.0%) |');
+      expect(report).not.toContain('(control)');
+    });
+  });
+
+  describe('startWorkloadGenerator', () => {
+    test('should refuse to start without arms to send load to', async () => {
+      await expect(orchestrator.startWorkloadGenerator({ enabled: true }))
+        .rejects.toThrow('No experiment containers');
+    });
+  });
+
+  describe('publishReportDashboard', () => {
+    test('should create a markdown dashboard through the NerdGraph client', async () => {
+      mockClient.createDashboard.mockResolvedValue({ guid: 'abc', name: 'NRDOT Experiment exp-001' });
+
+      const result = await orchestrator.publishReportDashboard(experiment, '# Report', {
+        dashboard_name: 'NRDOT Experiment ${experiment.id}'
+      });
+
+      expect(result.guid).toBe('abc');
+      expect(mockClient.createDashboard).toHaveBeenCalledWith('12345', {
+        name: 'NRDOT Experiment exp-001',
+        description: 'Compare profiles',
+        permissions: 'PUBLIC_READ_WRITE',
+        pages: [{
+          name: 'Report',
+          widgets: [{
+            title: 'Cost Test',
+            visualization: { id: 'viz.markdown' },
+            layout: { column: 1, row: 1, width: 12, height: 12 },
+            rawConfiguration: { text: '# Report' }
+          }]
+        }]
+      });
+    });
+  });
+});
@@ -0,0 +1,70 @@
+const { summarize, welchTTest, tTestPValue, requiredSampleSize, thinOverlapping } = require('../../../../lib/common/statistics');
+
+describe('statistics', () => {
+  describe('summarize', () => {
+    test('should compute mean and sample variance', () => {
+      expect(summarize([2, 4, 4, 4, 5, 5, 7, 9])).toEqual({ count: 8, mean: 5, variance: 32 / 7 });
+    });
+
+    test('should handle empty input', () => {
+      expect(summarize([])).toEqual({ count: 0, mean: 0, variance: 0 });
+    });
+  });
+
+  describe('tTestPValue', () => {
+    test('should match known critical values', () => {
+      expect(tTestPValue(2.228, 10)).toBeCloseTo(0.05, 3);
+      expect(tTestPValue(-3.169, 10)).toBeCloseTo(0.01, 3);
+      expect(tTestPValue(0, 5)).toBe(1);
+    });
+  });
+
+  describe('welchTTest', () => {
+    test('should detect a clear difference', () => {
+      const result = welchTTest([10, 11, 9, 10, 12, 10], [8, 7, 9, 8, 7, 8]);
+
+      expect(result.significant).toBe(true);
+      expect(result.pValue).toBeLessThan(0.01);
+      expect(result.t).toBeLessThan(0);
+    });
+
+    test('should not flag noise as significant', () => {
+      const result = welchTTest([100, 104, 108, 100, 104, 108], [97, 100, 103, 106, 97, 100]);
+
+      expect(result.significant).toBe(false);
+    });
+
+    test('should require at least two samples per arm', () => {
+      expect(welchTTest([1], [2, 3])).toBeNull();
+    });
+
+    test('should handle constant samples', () => {
+      expect(welchTTest([5, 5, 5], [5, 5, 5]).pValue).toBe(1);
+      expect(welchTTest([5, 5, 5], [4, 4, 4]).significant).toBe(true);
+    });
+  });
+
+  describe('requiredSampleSize', () => {
+    test('should need more samples for smaller effects', () => {
+      const control = [10, 11, 9, 10, 12, 10];
+      const large = requiredSampleSize(control, [8, 7, 9, 8, 7, 8]);
+      const small = requiredSampleSize(control, [9.8, 10.5, 9.4, 10.1, 11.6, 9.9]);
+
+      expect(small).toBeGreaterThan(large);
+    });
+
+    test('should return null when there is no difference', () => {
+      expect(requiredSampleSize([1, 2, 3], [1, 2, 3])).toBeNull();
+    });
+  });
+
+  describe('thinOverlapping', () => {
+    test('should keep one sample per query window', () => {
+      expect(thinOverlapping([1, 2, 3, 4, 5], 60, 30)).toEqual({ values: [1, 3, 5], step: 2 });
+    });
+
+    test('should keep every sample when windows do not overlap', () => {
+      expect(thinOverlapping([1, 2, 3], 30, 30)).toEqual({ values: [1, 2, 3], step: 1 });
+    });
+  });
+});
@@ -0,0 +1,136 @@
+const TemplateVariableBuilder = require('../../../../dashboard-generator/lib/template-variables');
+
+describe('TemplateVariableBuilder', () => {
+  let builder;
+
+  beforeEach(() => {
+    builder = new TemplateVariableBuilder('12345');
+  });
+
+  describe('buildVariables', () => {
+    test('should build NRQL variables from presets', () => {
+      const [host] = builder.buildVariables(['host']);
+
+      expect(host.name).toBe('host');
+      expect(host.type).toBe('NRQL');
+      expect(host.isMultiSelection).toBe(true);
+      expect(host.nrqlQuery.accountIds).toEqual([12345]);
+      expect(host.nrqlQuery.query).toContain('uniques(host.name)');
+      expect(host.defaultValues).toEqual([{ value: { string: '*' } }]);
+    });
+
+    test('should build the ring picker', () => {
+      const [ring] = builder.buildVariables(['ring']);
+
+      expect(ring.type).toBe('NRQL');
+      expect(ring.nrqlQuery.query).toContain('uniques(nrdot.ring)');
+      expect(builder.applyToQuery('SELECT count(*) FROM Metric', ['ring']))
+        .toBe('SELECT count(*) FROM Metric WHERE nrdot.ring IN ({{ring}})');
+    });
+
+    test('should build enum and string variables', () => {
+      const [env, team] = builder.buildVariables([
+        { name: 'env', type: 'enum', values: ['prod', 'staging'] },
+        { name: 'team', type: 'string', default: 'platform' }
+      ]);
+
+      expect(env.type).toBe('ENUM');
+      expect(env.items).toEqual([
+        { title: 'prod', value: 'prod' },
+        { title: 'staging', value: 'staging' }
+      ]);
+      expect(team.type).toBe('STRING');
+      expect(team.isMultiSelection).toBe(false);
+      expect(team.defaultValues).toEqual([{ value: { string: 'platform' } }]);
+    });
+
+    test('should apply overrides to presets', () => {
+      const [profile] = builder.buildVariables([{ preset: 'profile', title: 'Profile', default: ['balanced', 'aggressive'] }]);
+
+      expect(profile.title).toBe('Profile');
+      expect(profile.defaultValues).toHaveLength(2);
+    });
+
+    test('should reject unknown presets and invalid definitions', () => {
+      expect(() => builder.buildVariables(['datacenter'])).toThrow("Unknown template variable preset 'datacenter'");
+      expect(() => builder.buildVariables([{ name: 'env', type: 'enum' }])).toThrow('requires at least one value');
+      expect(() => builder.buildVariables(['host', 'host'])).toThrow('defined more than once');
+      expect(() => builder.buildVariables([{ name: 'team', type: 'string', attribute: 'team' }]))
+        .toThrow('requires a default value');
+    });
+  });
+
+  describe('applyToQuery', () => {
+    test('should merge filters into an existing WHERE clause', () => {
+      const query = builder.applyToQuery(
+        "SELECT average(cpu) FROM Metric WHERE metricName = 'x' TIMESERIES",
+        ['host', 'profile']
+      );
+
+      expect(query).toBe(
+        "SELECT average(cpu) FROM Metric WHERE host.name IN ({{host}}) AND nrdot.profile IN ({{profile}}) AND (metricName = 'x') TIMESERIES"
+      );
+    });
+
+    test('should keep OR predicates grouped', () => {
+      const query = builder.applyToQuery(
+        "SELECT count(*) FROM Metric WHERE a = 'x' OR b = 'y' FACET host.name SINCE 1 hour ago",
+        ['host']
+      );
+
+      expect(query).toBe(
+        "SELECT count(*) FROM Metric WHERE host.name IN ({{host}}) AND (a = 'x' OR b = 'y') FACET host.name SINCE 1 hour ago"
+      );
+    });
+
+    test('should handle FROM-first queries', () => {
+      expect(builder.applyToQuery('FROM Metric SELECT count(*) WHERE x = 1', ['host']))
+        .toBe('FROM Metric SELECT count(*) WHERE host.name IN ({{host}}) AND (x = 1)');
+      expect(builder.applyToQuery('FROM Metric SELECT count(*)', ['host']))
+        .toBe('FROM Metric WHERE host.name IN ({{host}}) SELECT count(*)');
+    });
+
+    test('should accept backtick-quoted event types', () => {
+      expect(builder.applyToQuery('SELECT count(*) FROM `My-Event` SINCE 1 hour ago', ['host']))
+        .toBe('SELECT count(*) FROM `My-Event` WHERE host.name IN ({{host}}) SINCE 1 hour ago');
+    });
+
+    test('should return queries with an unparseable FROM clause unchanged', () => {
+      expect(builder.applyToQuery('SELECT count(*) FROM (SELECT 1)', ['host']))
+        .toBe('SELECT count(*) FROM (SELECT 1)');
+    });
+
+    test('should replace the fixed host pin with the host picker', () => {
+      const query = builder.applyToQuery(
+        "SELECT latest(m) FROM Metric WHERE host.id = 'dashbuilder-host' AND device != 'lo' SINCE 5 minutes ago",
+        ['host']
+      );
+
+      expect(query).toBe(
+        "SELECT latest(m) FROM Metric WHERE host.name IN ({{host}}) AND (device != 'lo') SINCE 5 minutes ago"
+      );
+    });
+
+    test('should add a WHERE clause and leave FILTER conditions alone', () => {
+      const query = builder.applyToQuery(
+        'SELECT filter(count(*), WHERE value > 1) FROM Metric FACET host.name',
+        ['process_group']
+      );
+
+      expect(query).toBe(
+        'SELECT filter(count(*), WHERE value > 1) FROM Metric WHERE process.classification IN ({{process_group}}) FACET host.name'
+      );
+    });
+
+    test('should not add a filter for variables already referenced', () => {
+      const query = 'SELECT count(*) FROM Metric WHERE host.name IN ({{host}})';
+
+      expect(builder.applyToQuery(query, ['host'])).toBe(query);
+    });
+  });
+
+  test('findReferences should list variable placeholders', () => {
+    expect(builder.findReferences('SELECT count(*) FROM Metric WHERE a = {{team}} AND b IN ({{ host }})'))
+      .toEqual(['team', 'host']);
+  });
+});
@@ -0,0 +1,105 @@
+const { VerifyService } = require('../../../../scripts/src/services/verify.service');
+
+describe('VerifyService', () => {
+  const now = Date.parse('2024-01-01T12:00:00Z');
+  let service;
+
+  beforeEach(() => {
+    service = new VerifyService({
+      apiKey: 'test-key',
+      accountId: '12345',
+      region: 'US',
+      requireAccountId: () => '12345'
+    });
+    service.client = { nrql: jest.fn() };
+  });
+
+  describe('evaluate', () => {
+    test('should report fresh rings as healthy', () => {
+      const result = service.evaluate([
+        { facet: '1', dataPoints: 40, hosts: 2, lastSeen: now - 30000 },
+        { facet: '0', dataPoints: 120, hosts: 3, lastSeen: now - 60000 }
+      ], { now, maxAgeSeconds: 300 });
+
+      expect(result.healthy).toBe(true);
+      expect(result.totalDataPoints).toBe(160);
+      expect(result.rings.map(r => r.ring)).toEqual(['0', '1']);
+      expect(result.rings[0].ageSeconds).toBe(60);
+      expect(result.rings[0].status).toBe('ok');
+    });
+
+    test('should flag stale and missing rings', () => {
+      const result = service.evaluate([
+        { facet: '0', dataPoints: 10, hosts: 1, lastSeen: now - 600000 }
+      ], { now, maxAgeSeconds: 300, expectedRings: ['0', '2'] });
+
+      expect(result.healthy).toBe(false);
+      expect(result.rings.find(r => r.ring === '0').status).toBe('stale');
+      expect(result.rings.find(r => r.ring === '2').status).toBe('missing');
+      expect(result.issues).toHaveLength(2);
+    });
+
+    test('should honour a max age of zero', () => {
+      const result = service.evaluate([
+        { facet: '0', dataPoints: 10, hosts: 1, lastSeen: now - 30000 }
+      ], { now, maxAgeSeconds: 0 });
+
+      expect(result.maxAgeSeconds).toBe(0);
+      expect(result.rings[0].status).toBe('stale');
+    });
+
+    test('should fail when no ring data is found', () => {
+      const result = service.evaluate([], { now });
+
+      expect(result.healthy).toBe(false);
+      expect(result.issues[0]).toContain('No process data');
+    });
+  });
+
+  test('verifyRings should query process data faceted by ring', async () => {
+    service.client.nrql.mockResolvedValue({
+      results: [{ facet: '0', dataPoints: 5, hosts: 1, lastSeen: Date.now() }]
+    });
+
+    const result = await service.verifyRings({ since: '5 minutes ago' });
+
+    expect(service.client.nrql).toHaveBeenCalledWith(
+      '12345',
+      expect.stringContaining('FACET nrdot.ring SINCE 5 minutes ago')
+    );
+    expect(result.healthy).toBe(true);
+  });
+
+  test('verifyRings should use the configured metric pattern', async () => {
+    service.client.nrql.mockResolvedValue({ results: [] });
+
+    await service.verifyRings({ metricPattern: 'system.%' });
+
+    expect(service.client.nrql).toHaveBeenCalledWith(
+      '12345',
+      expect.stringContaining("WHERE metricName LIKE 'system.%' AND nrdot.ring IS NOT NULL")
+    );
+  });
+
+  test('sendSelfCheckEvent should post one event per ring with the license key', async () => {
+    const originalFetch = global.fetch;
+    global.fetch = jest.fn().mockResolvedValue({ ok: true });
+
+    try {
+      const verification = service.evaluate([
+        { facet: '0', dataPoints: 10, hosts: 1, lastSeen: now },
+        { facet: '1', dataPoints: 5, hosts: 1, lastSeen: now }
+      ], { now });
+
+      const sent = await service.sendSelfCheckEvent(verification, 'license-key');
+      const [url, request] = global.fetch.mock.calls[0];
+
+      expect(sent).toBe(2);
+      expect(url).toBe('https://insights-collector.newrelic.com/v1/accounts/12345/events');
+      expect(request.headers['Api-Key']).toBe('license-key');
+      expect(JSON.parse(request.body).map(event => event['nrdot.ring'])).toEqual(['0', '1']);
+    } finally {
+      global.fetch = originalFetch;
+    }
+  });
+});
@@ -0,0 +1,61 @@
+const { WorkloadGenerator } = require('../../../../scripts/workload-generator');
+
+describe('WorkloadGenerator', () => {
+  const config = { processes: { total_count: 10 } };
+
+  test('should parse a comma-separated endpoint list', () => {
+    const generator = new WorkloadGenerator(config, {
+      endpoints: 'http://localhost:32768/, http://localhost:32769'
+    });
+
+    expect(generator.config.endpoints).toEqual(['http://localhost:32768', 'http://localhost:32769']);
+  });
+
+  test('sendTick should post the same payload to every endpoint', async () => {
+    const originalFetch = global.fetch;
+    const originalLog = console.log;
+    global.fetch = jest.fn().mockResolvedValue({ ok: true });
+    console.log = jest.fn();
+
+    try {
+      const generator = new WorkloadGenerator(config, {
+        seed: '7',
+        endpoints: 'http://localhost:32768,http://localhost:32769'
+      });
+
+      await generator.sendTick();
+
+      const [first, second] = global.fetch.mock.calls;
+      expect(global.fetch.mock.calls).toHaveLength(2);
+      expect(first[0]).toBe('http://localhost:32768/v1/metrics');
+      expect(second[0]).toBe('http://localhost:32769/v1/metrics');
+      expect(first[1].body).toBe(second[1].body);
+    } finally {
+      global.fetch = originalFetch;
+      console.log = originalLog;
+    }
+  });
+
+  test('sendTick should report failed exports per endpoint', async () => {
+    const originalFetch = global.fetch;
+    const originalLog = console.log;
+    const originalError = console.error;
+    global.fetch = jest.fn().mockResolvedValue({ ok: false, status: 503, statusText: 'Service Unavailable' });
+    console.log = jest.fn();
+    console.error = jest.fn();
+
+    try {
+      const generator = new WorkloadGenerator(config, { endpoints: 'http://localhost:32768' });
+
+      await generator.sendTick();
+
+      expect(console.error).toHaveBeenCalledWith(
+        'OTLP export to http://localhost:32768 failed: 503 Service Unavailable'
+      );
+    } finally {
+      global.fetch = originalFetch;
+      console.log = originalLog;
+      console.error = originalError;
+    }
+  });
+});
@@ -0,0 +1,75 @@
+const { WorkloadModel, createRandom } = require('../../../../lib/common/workload-model');
+
+describe('WorkloadModel', () => {
+  const config = {
+    processes: {
+      total_count: 50,
+      distribution: { critical: 10, important: 15, standard: 25 },
+      activity_patterns: [
+        { name: 'steady', percentage: 50, cpu_range: [5, 20], memory_range: [100, 300] },
+        { name: 'variable', percentage: 30, cpu_range: [10, 60], memory_range: [200, 1000] },
+        { name: 'idle', percentage: 20, cpu_range: [0, 5], memory_range: [50, 100] }
+      ]
+    }
+  };
+
+  test('createRandom should be deterministic per seed', () => {
+    const a = createRandom(7);
+    const b = createRandom(7);
+
+    expect([a(), a(), a()]).toEqual([b(), b(), b()]);
+    expect(createRandom(8)()).not.toBe(createRandom(7)());
+  });
+
+  test('should reproduce the same population and samples for a seed', () => {
+    const first = new WorkloadModel(config, { seed: 42 });
+    const second = new WorkloadModel(config, { seed: 42 });
+
+    expect(first.tick()).toEqual(second.tick());
+    expect(first.tick()).toEqual(second.tick());
+  });
+
+  test('should keep the population at the target size through births and deaths', () => {
+    const model = new WorkloadModel({ ...config, churn_rate: 0.2 }, { seed: 3 });
+    let births = 0;
+    let deaths = 0;
+
+    for (let i = 0; i < 10; i++) {
+      const tick = model.tick();
+      expect(tick.samples).toHaveLength(50);
+      expect(tick.births).toBe(tick.deaths);
+      births += tick.births;
+      deaths += tick.deaths;
+    }
+
+    expect(births).toBeGreaterThan(0);
+    expect(deaths).toBe(births);
+  });
+
+  test('should keep samples within the configured ranges', () => {
+    const model = new WorkloadModel(config, { seed: 5 });
+    const { samples } = model.tick();
+
+    samples.forEach(sample => {
+      expect(sample.cpuPercent).toBeGreaterThanOrEqual(0);
+      expect(sample.cpuPercent).toBeLessThanOrEqual(60);
+      expect(sample.memoryBytes).toBeLessThanOrEqual(1000 * 1024 * 1024 * 1.05);
+      expect(['critical', 'important', 'standard']).toContain(sample.classification);
+    });
+  });
+
+  test('toOtlp should emit process metrics per resource', () => {
+    const model = new WorkloadModel({ processes: { total_count: 2 } }, { seed: 1 });
+    const payload = model.toOtlp(model.tick(), 1700000000000);
+
+    expect(payload.resourceMetrics).toHaveLength(2);
+    const [resource] = payload.resourceMetrics;
+    expect(resource.resource.attributes.map(a => a.key)).toEqual([
+      'service.name', 'host.name', 'process.pid', 'process.executable.name', 'process.classification'
+    ]);
+    expect(resource.scopeMetrics[0].metrics.map(m => m.name)).toEqual([
+      'process.cpu.utilization', 'process.memory.usage'
+    ]);
+    expect(resource.scopeMetrics[0].metrics[0].gauge.dataPoints[0].timeUnixNano).toBe('1700000000000000000');
+  });
+});